@@ -81,7 +81,7 @@ func StartChain(ctx *cli.Context, chain *Chain, startDone chan<- struct{}) error
 	return nil
 }
 
-func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivValidator, keyJson []byte, validators []tdmTypes.GenesisValidator) error {
+func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivValidator, keyJson []byte, validators []tdmTypes.GenesisValidator, genesisParams []byte) error {
 
 	// Get Tendermint config base on chain id
 	config := GetTendermintConfig(chainId, ctx)
@@ -101,8 +101,14 @@ func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivV
 	validator.SetFile(privValFile + ".json")
 	validator.Save()
 
+	// Parse the Genesis Parameters, which has been validated by CanCreateChildChain
+	gp, err := ParseChildChainGenesisParams(genesisParams)
+	if err != nil {
+		return err
+	}
+
 	// Init the Ethereum Genesis
-	err := initEthGenesisFromExistValidator(chainId, config, validators)
+	err = initEthGenesisFromExistValidator(chainId, config, validators, gp)
 	if err != nil {
 		return err
 	}
@@ -111,7 +117,7 @@ func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivV
 	init_eth_blockchain(chainId, config.GetString("eth_genesis_file"), ctx)
 
 	// Init the Tendermint Genesis
-	init_em_files(config, chainId, config.GetString("eth_genesis_file"), validators)
+	init_em_files(config, chainId, config.GetString("eth_genesis_file"), validators, gp)
 
 	return nil
 }
@@ -9,10 +9,12 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/tendermint/types"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	pabi "github.com/pchain/abi"
 	"github.com/pchain/p2p"
 	"github.com/pchain/rpc"
 	"github.com/pkg/errors"
@@ -20,6 +22,7 @@ import (
 	dbm "github.com/tendermint/go-db"
 	"gopkg.in/urfave/cli.v1"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path"
@@ -90,12 +93,24 @@ func (cm *ChainManager) LoadChains(childIds []string) error {
 
 	// Check we are belong to the validator of Child Chain in DB first (Mining Mode)
 	for _, chainId := range childChainIds {
+		// Retired Child Chain will not be loaded any more
+		if core.IsChildChainRetired(cm.cch.chainInfoDB, chainId) {
+			log.Infof("Child Chain %s has retired, skip it", chainId)
+			continue
+		}
+
 		// Check Current Validator is Child Chain Validator
 		ci := core.GetChainInfo(cm.cch.chainInfoDB, chainId)
 		// Check if we are in this child chain
 		if ci.Epoch != nil && cm.checkCoinbaseInChildChain(ci.Epoch) {
 			readyToLoadChains[chainId] = true
 		}
+		// Check if we have joined this child chain and wait for the next epoch
+		for _, qv := range core.GetJoinQueue(cm.cch.chainInfoDB, chainId) {
+			if cm.isLocalEtherbase(qv.Address) {
+				readyToLoadChains[chainId] = true
+			}
+		}
 	}
 
 	// Check request from Child Chain
@@ -105,6 +120,11 @@ func (cm *ChainManager) LoadChains(childIds []string) error {
 			continue
 		}
 
+		if core.IsChildChainRetired(cm.cch.chainInfoDB, requestId) {
+			log.Infof("Child Chain %s has retired, ignore the request", requestId)
+			continue
+		}
+
 		if _, present := readyToLoadChains[requestId]; present {
 			// Already loaded, ignore
 			continue
@@ -264,8 +284,12 @@ func (cm *ChainManager) StartInspectEvent() {
 	createChildChainCh := make(chan core.CreateChildChainEvent, 10)
 	createChildChainSub := MustGetEthereumFromNode(cm.mainChain.EthNode).BlockChain().SubscribeCreateChildChainEvent(createChildChainCh)
 
+	joinChildChainCh := make(chan core.JoinChildChainEvent, 10)
+	joinChildChainSub := MustGetEthereumFromNode(cm.mainChain.EthNode).BlockChain().SubscribeJoinChildChainEvent(joinChildChainCh)
+
 	go func() {
 		defer createChildChainSub.Unsubscribe()
+		defer joinChildChainSub.Unsubscribe()
 
 		for {
 			select {
@@ -278,8 +302,24 @@ func (cm *ChainManager) StartInspectEvent() {
 
 					cm.LoadChildChainInRT(event.ChainId)
 				}()
+			case event := <-joinChildChainCh:
+				log.Infof("JoinChildChainEvent received: %v", event)
+
+				if !cm.isLocalEtherbase(event.Address) {
+					continue
+				}
+
+				go func() {
+					cm.createChildChainLock.Lock()
+					defer cm.createChildChainLock.Unlock()
+
+					cm.LoadJoinedChildChainInRT(event.ChainId)
+					cm.confirmJoinChildChain(event.ChainId, event.Address, event.TxHash)
+				}()
 			case <-createChildChainSub.Err():
 				return
+			case <-joinChildChainSub.Err():
+				return
 			}
 		}
 	}()
@@ -337,6 +377,104 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 		return
 	}
 
+	if err := cm.createChildChainData(chainId, localEtherbase, validators, cci.GenesisParams); err != nil {
+		log.Errorf("Create Child Chain %v failed! %v", chainId, err)
+		return
+	}
+
+	chain := LoadChildChain(cm.ctx, chainId)
+	if chain == nil {
+		log.Errorf("Child Chain %v load failed!", chainId)
+		return
+	}
+
+	if err := cm.startChildChainInRT(chain); err != nil {
+		return
+	}
+
+	var childEthereum *eth.Ethereum
+	chain.EthNode.Service(&childEthereum)
+	firstEpoch := childEthereum.Engine().(consensus.Tendermint).GetEpoch()
+	// Child Chain start success, then delete the pending data in chain info db
+	cm.formalizeChildChain(chainId, *cci, firstEpoch)
+
+	// Add Child Chain Id into Chain Manager
+	cm.childChains[chainId] = chain
+
+	//TODO Broadcast Child ID to all Main Chain peers
+	go cm.server.BroadcastNewChildChainMsg(chainId)
+
+	cm.hookupChildChainRPC(chain)
+}
+
+// LoadJoinedChildChainInRT load the launched child chain which the local validator has just joined in the main chain,
+// the child chain will catch up and start mining once the local validator is in the epoch validators
+func (cm *ChainManager) LoadJoinedChildChainInRT(chainId string) {
+
+	ci := core.GetChainInfo(cm.cch.chainInfoDB, chainId)
+	if ci == nil {
+		log.Errorf("child chain: %s has not been launched, can't load", chainId)
+		return
+	}
+
+	// if child chain already loaded, just return
+	if _, ok := cm.childChains[chainId]; ok {
+		log.Infof("Child Chain [%v] has been already loaded.", chainId)
+		return
+	}
+
+	var ethereum *eth.Ethereum
+	cm.mainChain.EthNode.Service(&ethereum)
+
+	var localEtherbase common.Address
+	if tdm, ok := ethereum.Engine().(consensus.Tendermint); ok {
+		localEtherbase = tdm.PrivateValidator()
+	}
+
+	// Create the child chain data from the validators at launch, so the genesis is the same with the running child chain
+	config := GetTendermintConfig(chainId, cm.ctx)
+	if _, err := os.Stat(config.GetString("eth_genesis_file")); os.IsNotExist(err) {
+		validators := make([]types.GenesisValidator, 0, len(ci.JoinedValidators))
+		for _, v := range ci.JoinedValidators {
+			// dereference the PubKey
+			if pubkey, ok := v.PubKey.(*crypto.BLSPubKey); ok {
+				v.PubKey = *pubkey
+			}
+
+			validators = append(validators, types.GenesisValidator{
+				EthAccount: v.Address,
+				PubKey:     v.PubKey,
+				Amount:     v.DepositAmount,
+			})
+		}
+
+		if err := cm.createChildChainData(chainId, localEtherbase, validators, ci.GenesisParams); err != nil {
+			log.Errorf("Create Child Chain %v failed! %v", chainId, err)
+			return
+		}
+	}
+
+	chain := LoadChildChain(cm.ctx, chainId)
+	if chain == nil {
+		log.Errorf("Child Chain %v load failed!", chainId)
+		return
+	}
+
+	if err := cm.startChildChainInRT(chain); err != nil {
+		return
+	}
+
+	// Add Child Chain Id into Chain Manager
+	cm.childChains[chainId] = chain
+
+	go cm.server.BroadcastNewChildChainMsg(chainId)
+
+	cm.hookupChildChainRPC(chain)
+}
+
+// createChildChainData create the data dir, key store, validator file and genesis of the child chain
+func (cm *ChainManager) createChildChainData(chainId string, localEtherbase common.Address, validators []types.GenesisValidator, genesisParams []byte) error {
+
 	// Load the KeyStore file from MainChain (Optional)
 	var keyJson []byte
 	wallet, walletErr := cm.mainChain.EthNode.AccountManager().Find(accounts.Account{Address: localEtherbase})
@@ -352,17 +490,11 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 	privValidatorFile := cm.mainChain.Config.GetString("priv_validator_file")
 	self := types.LoadPrivValidator(privValidatorFile)
 
-	err := CreateChildChain(cm.ctx, chainId, *self, keyJson, validators)
-	if err != nil {
-		log.Errorf("Create Child Chain %v failed! %v", chainId, err)
-		return
-	}
+	return CreateChildChain(cm.ctx, chainId, *self, keyJson, validators, genesisParams)
+}
 
-	chain := LoadChildChain(cm.ctx, chainId)
-	if chain == nil {
-		log.Errorf("Child Chain %v load failed!", chainId)
-		return
-	}
+// startChildChainInRT attach the child chain to the p2p server and start it
+func (cm *ChainManager) startChildChainInRT(chain *Chain) error {
 
 	//StartChildChain to attach p2p and rpc
 	//TODO Hookup new Created Child Chain to P2P server
@@ -381,42 +513,31 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 
 	// Start the new Child Chain, and it will start child chain reactors as well
 	startDone := make(chan struct{})
-	err = StartChain(cm.ctx, chain, startDone)
+	err := StartChain(cm.ctx, chain, startDone)
 	<-startDone
 	if err != nil {
-		return
+		return err
 	}
 
 	cm.childQuits[chain.Id] = chain.EthNode.StopChan()
+	return nil
+}
 
-	var childEthereum *eth.Ethereum
-	chain.EthNode.Service(&childEthereum)
-	firstEpoch := childEthereum.Engine().(consensus.Tendermint).GetEpoch()
-	// Child Chain start success, then delete the pending data in chain info db
-	cm.formalizeChildChain(chainId, *cci, firstEpoch)
-
-	// Add Child Chain Id into Chain Manager
-	cm.childChains[chainId] = chain
-
-	//TODO Broadcast Child ID to all Main Chain peers
-	go cm.server.BroadcastNewChildChainMsg(chainId)
-
-	//hookup rpc
+func (cm *ChainManager) hookupChildChainRPC(chain *Chain) {
 	if rpc.IsHTTPRunning() {
 		if h, err := chain.EthNode.GetHTTPHandler(); err == nil {
 			rpc.HookupHTTP(chain.Id, h)
 		} else {
-			log.Errorf("Unable Hook up Child Chain (%v) RPC HTTP Handler: %v", chainId, err)
+			log.Errorf("Unable Hook up Child Chain (%v) RPC HTTP Handler: %v", chain.Id, err)
 		}
 	}
 	if rpc.IsWSRunning() {
 		if h, err := chain.EthNode.GetWSHandler(); err == nil {
 			rpc.HookupWS(chain.Id, h)
 		} else {
-			log.Errorf("Unable Hook up Child Chain (%v) RPC WS Handler: %v", chainId, err)
+			log.Errorf("Unable Hook up Child Chain (%v) RPC WS Handler: %v", chain.Id, err)
 		}
 	}
-
 }
 
 func (cm *ChainManager) formalizeChildChain(chainId string, cci core.CoreChainInfo, ep *epoch.Epoch) {
@@ -426,6 +547,61 @@ func (cm *ChainManager) formalizeChildChain(chainId string, cci core.CoreChainIn
 	core.SaveChainInfo(cm.cch.chainInfoDB, &core.ChainInfo{CoreChainInfo: cci, Epoch: ep})
 }
 
+// confirmJoinChildChain send the ConfirmJoinChildChain tx of the local validator who has just joined in the main chain,
+// then the validator will be picked up into the next epoch of the child chain
+func (cm *ChainManager) confirmJoinChildChain(chainId string, address common.Address, txHash common.Hash) {
+
+	chain, ok := cm.childChains[chainId]
+	if !ok {
+		return
+	}
+
+	input, err := pabi.ChainABI.Pack(pabi.ConfirmJoinChildChain.String(), chainId, txHash)
+	if err != nil {
+		log.Errorf("confirmJoinChildChain: failed to pack the input, %v", err)
+		return
+	}
+
+	childEthereum := MustGetEthereumFromNode(chain.EthNode)
+	txPool := childEthereum.TxPool()
+
+	// The tx is sent from the joiner, signed by the etherbase account of the main chain
+	nonce := txPool.State().GetNonce(address)
+	signedTx, err := signConfirmJoinTx(cm.mainChain.EthNode.AccountManager(), address, nonce, txPool.GasPrice(), childEthereum.BlockChain().Config().ChainId, input)
+	if err != nil {
+		log.Errorf("confirmJoinChildChain: failed to sign the tx, %v", err)
+		return
+	}
+
+	if err := txPool.AddLocal(signedTx); err != nil {
+		log.Errorf("confirmJoinChildChain: failed to send the tx, %v", err)
+		return
+	}
+	log.Infof("confirmJoinChildChain: join tx %x sent to child chain %s, hash: %x", txHash, chainId, signedTx.Hash())
+}
+
+// signConfirmJoinTx builds the ConfirmJoinChildChain tx and signs it with the account of the joiner
+func signConfirmJoinTx(am *accounts.Manager, joiner common.Address, nonce uint64, gasPrice, chainId *big.Int, input []byte) (*ethTypes.Transaction, error) {
+	account := accounts.Account{Address: joiner}
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := ethTypes.NewTransaction(nonce, pabi.ChainContractMagicAddr, nil, pabi.ConfirmJoinChildChain.RequiredGas(), gasPrice, input)
+	return wallet.SignTx(account, tx, chainId)
+}
+
+func (cm *ChainManager) isLocalEtherbase(address common.Address) bool {
+	var ethereum *eth.Ethereum
+	cm.mainChain.EthNode.Service(&ethereum)
+
+	if tdm, ok := ethereum.Engine().(consensus.Tendermint); ok {
+		return tdm.PrivateValidator() == address
+	}
+	return false
+}
+
 func (cm *ChainManager) checkCoinbaseInChildChain(childEpoch *epoch.Epoch) bool {
 	var ethereum *eth.Ethereum
 	cm.mainChain.EthNode.Service(&ethereum)
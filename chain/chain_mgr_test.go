@@ -0,0 +1,59 @@
+package chain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	pabi "github.com/pchain/abi"
+)
+
+func TestSignConfirmJoinTx(t *testing.T) {
+	dir, err := ioutil.TempDir("", "confirm-join-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	joiner, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatalf("failed to create the account: %v", err)
+	}
+	if err := ks.Unlock(joiner, ""); err != nil {
+		t.Fatalf("failed to unlock the account: %v", err)
+	}
+	am := accounts.NewManager(ks)
+	defer am.Close()
+
+	input, err := pabi.ChainABI.Pack(pabi.ConfirmJoinChildChain.String(), "child_0", [32]byte{1})
+	if err != nil {
+		t.Fatalf("failed to pack the input: %v", err)
+	}
+	chainId := big.NewInt(77)
+	tx, err := signConfirmJoinTx(am, joiner.Address, 3, big.NewInt(1), chainId, input)
+	if err != nil {
+		t.Fatalf("failed to sign the tx: %v", err)
+	}
+
+	from, err := ethTypes.Sender(ethTypes.NewEIP155Signer(chainId), tx)
+	if err != nil {
+		t.Fatalf("failed to recover the sender: %v", err)
+	}
+	if from != joiner.Address {
+		t.Errorf("sender mismatch: have %x, want %x", from, joiner.Address)
+	}
+	if tx.Nonce() != 3 || tx.Gas() < pabi.ConfirmJoinChildChain.RequiredGas() {
+		t.Errorf("tx mismatch: nonce %d, gas %d", tx.Nonce(), tx.Gas())
+	}
+
+	// The account not in the key store can't sign the tx
+	if _, err := signConfirmJoinTx(am, common.Address{1}, 0, big.NewInt(1), chainId, input); err == nil {
+		t.Errorf("tx signed for the unknown account")
+	}
+}
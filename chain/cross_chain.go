@@ -35,6 +35,7 @@ import (
 const (
 	OFFICIAL_MINIMUM_VALIDATORS = 1
 	OFFICIAL_MINIMUM_DEPOSIT    = "100000000000000000000000" // 100,000 * e18
+	OFFICIAL_STARTUP_COST       = OFFICIAL_MINIMUM_DEPOSIT   // startup cost of the child chain, refunded to the owner when it retires
 )
 
 type CrossChainHelper struct {
@@ -44,6 +45,8 @@ type CrossChainHelper struct {
 	//the client does only connect to main chain
 	client      *ethclient.Client
 	mainChainId string
+
+	solvency solvencyAuditor
 }
 
 func (cch *CrossChainHelper) GetMutex() *sync.Mutex {
@@ -63,7 +66,7 @@ func (cch *CrossChainHelper) GetMainChainId() string {
 }
 
 // CanCreateChildChain check the condition before send the create child chain into the tx pool
-func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int) error {
+func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int, genesisParams []byte) error {
 
 	if chainId == "" || strings.Contains(chainId, ";") {
 		return errors.New("chainId is nil or empty, or contains ';', should be meaningful")
@@ -106,8 +109,9 @@ func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId st
 	}
 
 	// Check the startup cost
-	if startupCost.Cmp(officialMinimumDeposit) != 0 {
-		return fmt.Errorf("Startup cost is not meet the required amount (%v PI)", new(big.Int).Div(officialMinimumDeposit, big.NewInt(params.PI)))
+	officialStartupCost := math.MustParseBig256(OFFICIAL_STARTUP_COST)
+	if startupCost.Cmp(officialStartupCost) != 0 {
+		return fmt.Errorf("Startup cost is not meet the required amount (%v PI)", new(big.Int).Div(officialStartupCost, big.NewInt(params.PI)))
 	}
 
 	// Check start/end block
@@ -122,11 +126,16 @@ func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId st
 		return errors.New("end block number has already passed")
 	}
 
+	// Check the genesis parameters (Optional)
+	if _, err := ParseChildChainGenesisParams(genesisParams); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // CreateChildChain Save the Child Chain Data into the DB, the data will be used later during Block Commit Callback
-func (cch *CrossChainHelper) CreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount *big.Int, startBlock, endBlock *big.Int) error {
+func (cch *CrossChainHelper) CreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount *big.Int, startBlock, endBlock *big.Int, genesisParams []byte) error {
 	log.Debug("CreateChildChain - start")
 
 	cci := &core.CoreChainInfo{
@@ -137,6 +146,9 @@ func (cch *CrossChainHelper) CreateChildChain(from common.Address, chainId strin
 		StartBlock:       startBlock,
 		EndBlock:         endBlock,
 		JoinedValidators: make([]core.JoinedValidator, 0),
+		GenesisParams:    genesisParams,
+
+		BalanceStatTracked: true,
 	}
 	core.CreatePendingChildChainData(cch.chainInfoDB, cci)
 
@@ -160,8 +172,8 @@ func (cch *CrossChainHelper) ValidateJoinChildChain(from common.Address, consens
 	// Check if "chainId" has been created/registered
 	ci := core.GetPendingChildChainData(cch.chainInfoDB, chainId)
 	if ci == nil {
-		if core.GetChainInfo(cch.chainInfoDB, chainId) != nil {
-			return fmt.Errorf("chain %s has already created/started, try use other name instead", chainId)
+		if launched := core.GetChainInfo(cch.chainInfoDB, chainId); launched != nil && cch.isJoinLaunchedChildChainActive() {
+			return cch.validateJoinLaunchedChildChain(from, launched, depositAmount)
 		} else {
 			return fmt.Errorf("child chain %s not exist, try use other name instead", chainId)
 		}
@@ -189,13 +201,54 @@ func (cch *CrossChainHelper) ValidateJoinChildChain(from common.Address, consens
 	return nil
 }
 
-// JoinChildChain Join the Child Chain
-func (cch *CrossChainHelper) JoinChildChain(from common.Address, pubkey crypto.PubKey, chainId string, depositAmount *big.Int) error {
+// isJoinLaunchedChildChainActive checks the fork of joining the launched child chain against the pending block of the main chain
+func (cch *CrossChainHelper) isJoinLaunchedChildChainActive() bool {
+	bc := MustGetEthereumFromNode(chainMgr.mainChain.EthNode).BlockChain()
+	return bc.Config().IsJoinLaunchedChildChain(new(big.Int).Add(bc.CurrentBlock().Number(), common.Big1))
+}
+
+// validateJoinLaunchedChildChain check the criteria for joining the child chain which has been launched
+func (cch *CrossChainHelper) validateJoinLaunchedChildChain(from common.Address, ci *core.ChainInfo, depositAmount *big.Int) error {
+
+	if !core.CheckChildChainRunning(cch.chainInfoDB, ci.ChainId) || core.IsChildChainClosing(cch.chainInfoDB, ci.ChainId) {
+		return fmt.Errorf("child chain %s is not running, you can't join the chain", ci.ChainId)
+	}
+
+	if ci.Epoch != nil && ci.Epoch.Validators.HasAddress(from.Bytes()) {
+		return fmt.Errorf("You are already the validator of the Child Chain %s", ci.ChainId)
+	}
+
+	if core.GetQueuedValidator(cch.chainInfoDB, ci.ChainId, from) != nil {
+		return fmt.Errorf("You have already joined the Child Chain %s, please wait for the next epoch", ci.ChainId)
+	}
+
+	if !(depositAmount != nil && depositAmount.Sign() == 1) {
+		return errors.New("deposit amount must be greater than 0")
+	}
+
+	return nil
+}
+
+// JoinChildChain Join the Child Chain, validator who joins the launched child chain will be queued for the next epoch of the child chain
+func (cch *CrossChainHelper) JoinChildChain(from common.Address, pubkey crypto.PubKey, chainId string, depositAmount *big.Int, txHash common.Hash) error {
 	log.Debug("JoinChildChain - start")
 
 	// Load the Child Chain first
 	ci := core.GetPendingChildChainData(cch.chainInfoDB, chainId)
 	if ci == nil {
+		if launched := core.GetChainInfo(cch.chainInfoDB, chainId); launched != nil {
+			core.AddToJoinQueue(cch.chainInfoDB, chainId, core.QueuedValidator{
+				PubKey:        pubkey,
+				Address:       from,
+				DepositAmount: depositAmount,
+				TxHash:        txHash,
+				EpochNumber:   launched.EpochNumber,
+			})
+			log.Infof("JoinChildChain - %x joined the launched Child Chain %s, waiting for the next epoch", from, chainId)
+			log.Debug("JoinChildChain - end")
+			return nil
+		}
+
 		log.Errorf("JoinChildChain - Child Chain %s not exist, you can't join the chain", chainId)
 		return fmt.Errorf("Child Chain %s not exist, you can't join the chain", chainId)
 	}
@@ -238,6 +291,140 @@ func (cch *CrossChainHelper) ProcessPostPendingData(newPendingIdxBytes []byte, d
 	core.ProcessPostPendingData(cch.chainInfoDB, newPendingIdxBytes, deleteChildChainIds)
 }
 
+// ValidateCloseChildChain check whether the address is able to close the child chain, either the owner or a validator who hasn't voted
+func (cch *CrossChainHelper) ValidateCloseChildChain(from common.Address, chainId string) error {
+
+	if chainId == MainChain || chainId == TestnetChain {
+		return errors.New("you can't close PChain")
+	}
+
+	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
+	if ci == nil {
+		if core.GetPendingChildChainData(cch.chainInfoDB, chainId) != nil {
+			return fmt.Errorf("chain %s has not been launched yet", chainId)
+		}
+		return fmt.Errorf("child chain %s not exist", chainId)
+	}
+
+	ccd := core.GetCloseChainData(cch.chainInfoDB, chainId)
+	if ccd.IsRetired() {
+		return fmt.Errorf("chain %s has already retired", chainId)
+	}
+	if ccd.IsClosing() {
+		return fmt.Errorf("chain %s is closing already", chainId)
+	}
+
+	if from == ci.Owner {
+		return nil
+	}
+
+	if ci.Epoch == nil || !ci.Epoch.Validators.HasAddress(from.Bytes()) {
+		return fmt.Errorf("only the owner or the validators of chain %s are able to close the chain", chainId)
+	}
+
+	if ccd != nil && ccd.HasVoted(from) {
+		return fmt.Errorf("you have already voted to close chain %s", chainId)
+	}
+
+	return nil
+}
+
+// CloseChildChain record the close request, the chain start closing when it comes from the owner or +2/3 validators voting power,
+// and retires after the grace period
+func (cch *CrossChainHelper) CloseChildChain(from common.Address, chainId string, height *big.Int, gracePeriod uint64) error {
+	log.Debug("CloseChildChain - start")
+
+	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
+	if ci == nil {
+		return fmt.Errorf("child chain %s not exist", chainId)
+	}
+
+	ccd := core.GetCloseChainData(cch.chainInfoDB, chainId)
+	if ccd == nil {
+		ccd = &core.CloseChainData{ChainId: chainId}
+	}
+	if ccd.IsClosing() {
+		return nil
+	}
+
+	startClosing := from == ci.Owner
+	if !startClosing && ci.Epoch != nil {
+		if !ccd.HasVoted(from) {
+			ccd.Voters = append(ccd.Voters, from)
+		}
+
+		votedPower := new(big.Int)
+		for _, voter := range ccd.Voters {
+			if _, val := ci.Epoch.Validators.GetByAddress(voter.Bytes()); val != nil {
+				votedPower.Add(votedPower, val.VotingPower)
+			}
+		}
+		// voted power * 3 > total voting power * 2
+		twoThird := new(big.Int).Mul(ci.Epoch.Validators.TotalVotingPower(), big.NewInt(2))
+		startClosing = new(big.Int).Mul(votedPower, big.NewInt(3)).Cmp(twoThird) > 0
+	}
+
+	if startClosing {
+		ccd.CloseBlock = new(big.Int).Set(height)
+		ccd.RetireBlock = new(big.Int).Add(height, new(big.Int).SetUint64(gracePeriod))
+		ccd.StartupCost = math.MustParseBig256(OFFICIAL_STARTUP_COST)
+		log.Infof("CloseChildChain - chain %s start closing at block %v, will retire at block %v", chainId, ccd.CloseBlock, ccd.RetireBlock)
+	}
+
+	core.SaveCloseChainData(cch.chainInfoDB, ccd)
+
+	log.Debug("CloseChildChain - end")
+	return nil
+}
+
+func (cch *CrossChainHelper) ReadyForRetireChildChain(height *big.Int, stateDB *state.StateDB) []string {
+	log.Debug("ReadyForRetireChildChain - start")
+
+	retiredId := core.GetChildChainForRetire(cch.chainInfoDB, height, stateDB)
+	if len(retiredId) > 0 {
+		log.Infof("ReadyForRetireChildChain - %v child chain(s) to be retired in Block %v. %v", len(retiredId), height, retiredId)
+	}
+
+	log.Debug("ReadyForRetireChildChain - end")
+	return retiredId
+}
+
+func (cch *CrossChainHelper) ProcessPostRetireData(retiredChildChainIds []string) {
+	core.ProcessPostRetireData(cch.chainInfoDB, retiredChildChainIds)
+}
+
+// ConfirmJoinChildChain put the validator who joined in the main chain into the vote set of the next epoch,
+// or the epoch after next if the validators of the next epoch have been calculated (after reveal vote stage)
+func (cch *CrossChainHelper) ConfirmJoinChildChain(ep *epoch.Epoch, height uint64, from common.Address, pubkey crypto.PubKey, depositAmount *big.Int, txHash common.Hash) error {
+
+	epochNumber := ep.Number + 1
+	var voteSet *epoch.EpochValidatorVoteSet
+	if height < ep.GetRevealVoteEndHeight() && ep.GetNextEpoch() != nil {
+		voteSet = ep.GetNextEpoch().GetEpochValidatorVoteSet()
+	} else if height >= ep.GetRevealVoteEndHeight() {
+		epochNumber = ep.Number + 2
+	}
+	if voteSet == nil {
+		voteSet = epoch.LoadEpochVoteSet(ep.GetDB(), epochNumber)
+	}
+	if voteSet == nil {
+		voteSet = epoch.NewEpochValidatorVoteSet()
+	}
+
+	// Store as a revealed vote, it will be merged into the validator set when entering the epoch
+	voteSet.StoreVote(&epoch.EpochValidatorVote{
+		Address: from,
+		PubKey:  pubkey,
+		Amount:  depositAmount,
+		Salt:    "join",
+		TxHash:  txHash,
+	})
+	epoch.SaveEpochVoteSet(ep.GetDB(), epochNumber, voteSet)
+
+	log.Infof("ConfirmJoinChildChain - %x will join the validators in epoch %v", from, epochNumber)
+	return nil
+}
+
 func (cch *CrossChainHelper) VoteNextEpoch(ep *epoch.Epoch, from common.Address, voteHash common.Hash, txHash common.Hash) error {
 
 	voteSet := ep.GetNextEpoch().GetEpochValidatorVoteSet()
@@ -304,6 +491,29 @@ func (cch *CrossChainHelper) GetEpochFromMainChain() (string, *epoch.Epoch) {
 	return ethereum.ChainConfig().PChainId, ep
 }
 
+// GetRewardPoolFromChildChain returns the balance of the reward pool and the height of the child chain,
+// nil balance if the child chain is not running in this node
+func (cch *CrossChainHelper) GetRewardPoolFromChildChain(chainId string) (*big.Int, uint64) {
+
+	chainMgr.createChildChainLock.Lock()
+	chain, ok := chainMgr.childChains[chainId]
+	chainMgr.createChildChainLock.Unlock()
+	if !ok {
+		return nil, 0
+	}
+
+	ethereum, err := getEthereumFromNode(chain.EthNode)
+	if err != nil {
+		return nil, 0
+	}
+	childChain := ethereum.BlockChain()
+	childState, err := childChain.State()
+	if err != nil {
+		return nil, 0
+	}
+	return childState.GetBalance(pabi.ChildChainTokenIncentiveAddr), childChain.CurrentBlock().NumberU64()
+}
+
 func (cch *CrossChainHelper) ChangeValidators(chainId string) {
 
 	if chainMgr == nil {
@@ -455,6 +665,9 @@ func (cch *CrossChainHelper) SaveChildChainProofDataToMainChain(bs []byte) error
 				ci.Epoch = ep
 				core.SaveChainInfo(cch.chainInfoDB, ci)
 				log.Infof("Epoch saved from chain: %s, epoch: %v", chainId, ep)
+
+				// Remove the validators who have been in the epoch from the join queue
+				core.CleanJoinQueue(cch.chainInfoDB, chainId, ep)
 			}
 		}
 	}
@@ -559,7 +772,7 @@ func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transac
 	}
 
 	data := tx4.Data()
-	function, err := pabi.FunctionTypeFromId(data[:4])
+	function, err := pabi.FunctionTypeFromId(data)
 	if err != nil {
 		return err
 	}
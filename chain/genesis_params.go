@@ -0,0 +1,163 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	pabi "github.com/pchain/abi"
+	"math/big"
+)
+
+const (
+	defaultChildChainGasLimit    uint64 = 0x8000000
+	defaultChildChainEpochLength uint64 = 657000
+	defaultEpochNumberPerYear    uint64 = 12
+
+	maxChildChainGasLimit    uint64 = 0x100000000
+	minChildChainEpochLength uint64 = 1000
+	maxChildChainEpochLength uint64 = 10000000
+	maxEpochNumberPerYear    uint64 = 8760 // one epoch per hour
+	maxGenesisParamsSize            = 64 * 1024
+	maxGenesisAllocAccounts         = 256
+)
+
+// ChildChainGenesisParams is the optional genesis customization of the child chain, provided by the owner in CreateChildChain.
+// The raw json is stored in the chain info, every validator generates the same genesis from it.
+type ChildChainGenesisParams struct {
+	// Gas Limit of the genesis block
+	GasLimit uint64 `json:"gasLimit,omitempty"`
+	// Pre-allocated accounts and pre-deployed contracts (code + storage), funded from the startup cost
+	Alloc core.GenesisAlloc `json:"alloc,omitempty"`
+	// Blocks of the first epoch
+	EpochLength uint64 `json:"epochLength,omitempty"`
+	// Reward Scheme
+	EpochNumberPerYear uint64                `json:"epochNumberPerYear,omitempty"`
+	RewardPerBlock     *math.HexOrDecimal256 `json:"rewardPerBlock,omitempty"`
+	// Declining reward curve, takes precedence over RewardPerBlock, can be changed by the owner later
+	RewardSchedule *types.RewardSchedule `json:"rewardSchedule,omitempty"`
+	// Size of the validator set, can be changed by the owner later
+	ValidatorSetRules *types.ValidatorSetRules `json:"validatorSetRules,omitempty"`
+}
+
+// ParseChildChainGenesisParams decode and validate the genesis parameters, empty data means the default genesis
+func ParseChildChainGenesisParams(data []byte) (*ChildChainGenesisParams, error) {
+	gp := &ChildChainGenesisParams{}
+	if len(data) == 0 {
+		return gp, nil
+	}
+
+	if len(data) > maxGenesisParamsSize {
+		return nil, fmt.Errorf("genesis parameters exceed the maximum size (%v bytes)", maxGenesisParamsSize)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(gp); err != nil {
+		return nil, fmt.Errorf("invalid genesis parameters: %v", err)
+	}
+
+	if err := gp.validate(); err != nil {
+		return nil, err
+	}
+	return gp, nil
+}
+
+func (gp *ChildChainGenesisParams) validate() error {
+
+	if gp.GasLimit != 0 && (gp.GasLimit < params.MinGasLimit || gp.GasLimit > maxChildChainGasLimit) {
+		return fmt.Errorf("gas limit must be between %v and %v", params.MinGasLimit, maxChildChainGasLimit)
+	}
+
+	if gp.EpochLength != 0 && (gp.EpochLength < minChildChainEpochLength || gp.EpochLength > maxChildChainEpochLength) {
+		return fmt.Errorf("epoch length must be between %v and %v blocks", minChildChainEpochLength, maxChildChainEpochLength)
+	}
+
+	if gp.EpochNumberPerYear > maxEpochNumberPerYear {
+		return fmt.Errorf("epoch number per year must not be greater than %v", maxEpochNumberPerYear)
+	}
+
+	if gp.ValidatorSetRules != nil {
+		if err := gp.ValidatorSetRules.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(gp.Alloc) > maxGenesisAllocAccounts {
+		return fmt.Errorf("alloc accounts exceed the maximum count (%v)", maxGenesisAllocAccounts)
+	}
+
+	for addr, account := range gp.Alloc {
+		if addr == pabi.ChildChainTokenIncentiveAddr || addr == pabi.ChainContractMagicAddr {
+			return fmt.Errorf("alloc account %x is reserved", addr)
+		}
+		if account.Balance == nil || account.Balance.Sign() < 0 {
+			return fmt.Errorf("alloc account %x has invalid balance", addr)
+		}
+		// Deposit, Delegation and Candidate can only come from the joined validators
+		if account.Amount != nil || account.DelegateBalance != nil || account.DepositProxiedDetail != nil ||
+			account.Candidate || account.Commission != 0 || account.PrivateKey != nil {
+			return fmt.Errorf("alloc account %x can only set balance, nonce, code and storage", addr)
+		}
+	}
+
+	budget := math.MustParseBig256(OFFICIAL_STARTUP_COST)
+	if gp.TotalAlloc().Cmp(budget) > 0 {
+		return fmt.Errorf("total alloc balance exceeds the startup cost (%v PI)", new(big.Int).Div(budget, big.NewInt(params.PI)))
+	}
+
+	if gp.RewardPerBlock != nil {
+		reward := (*big.Int)(gp.RewardPerBlock)
+		if reward.Sign() < 0 {
+			return errors.New("reward per block can't be negative")
+		}
+		if reward.Cmp(budget) > 0 {
+			return fmt.Errorf("reward per block exceeds the startup cost (%v PI)", new(big.Int).Div(budget, big.NewInt(params.PI)))
+		}
+	}
+
+	if gp.RewardSchedule != nil {
+		if err := gp.RewardSchedule.Validate(); err != nil {
+			return err
+		}
+		if gp.RewardSchedule.InitialReward.Cmp(budget) > 0 {
+			return fmt.Errorf("initial reward exceeds the startup cost (%v PI)", new(big.Int).Div(budget, big.NewInt(params.PI)))
+		}
+	}
+
+	return nil
+}
+
+// TotalAlloc return the total balance of the pre-allocated accounts
+func (gp *ChildChainGenesisParams) TotalAlloc() *big.Int {
+	total := big.NewInt(0)
+	for _, account := range gp.Alloc {
+		if account.Balance != nil {
+			total.Add(total, account.Balance)
+		}
+	}
+	return total
+}
+
+// applyAlloc add the pre-allocated accounts into the genesis, the rest of the startup cost goes to the child chain token incentive address
+func (gp *ChildChainGenesisParams) applyAlloc(alloc core.GenesisAlloc, startupCost *big.Int) {
+	for addr, account := range gp.Alloc {
+		if exist, ok := alloc[addr]; ok {
+			// Validator Account, keep the deposit
+			account.Amount = exist.Amount
+		} else {
+			account.Amount = common.Big0
+		}
+		alloc[addr] = account
+	}
+
+	alloc[pabi.ChildChainTokenIncentiveAddr] = core.GenesisAccount{
+		Balance: new(big.Int).Sub(startupCost, gp.TotalAlloc()),
+		Amount:  common.Big0,
+	}
+}
@@ -3,7 +3,6 @@ package chain
 import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/pchain/abi"
 	"os"
 	"path/filepath"
 
@@ -90,7 +89,7 @@ func init_cmd(ctx *cli.Context, config cfg.Config, chainId string, ethGenesisPat
 
 	init_eth_blockchain(chainId, ethGenesisPath, ctx)
 
-	init_em_files(config, chainId, ethGenesisPath, nil)
+	init_em_files(config, chainId, ethGenesisPath, nil, nil)
 
 	return nil
 }
@@ -181,7 +180,7 @@ func init_eth_blockchain(chainId string, ethGenesisPath string, ctx *cli.Context
 	log.Infof("successfully wrote genesis block and/or chain rule set: %x", block.Hash())
 }
 
-func init_em_files(config cfg.Config, chainId string, genesisPath string, validators []types.GenesisValidator) error {
+func init_em_files(config cfg.Config, chainId string, genesisPath string, validators []types.GenesisValidator, genesisParams *ChildChainGenesisParams) error {
 	gensisFile, err := os.Open(genesisPath)
 	defer gensisFile.Close()
 	if err != nil {
@@ -211,14 +210,14 @@ func init_em_files(config cfg.Config, chainId string, genesisPath string, valida
 	}
 
 	// Create the Genesis Doc
-	if err := createGenesisDoc(config, chainId, &coreGenesis, privValidator, validators); err != nil {
+	if err := createGenesisDoc(config, chainId, &coreGenesis, privValidator, validators, genesisParams); err != nil {
 		utils.Fatalf("failed to write genesis file: %v", err)
 		return err
 	}
 	return nil
 }
 
-func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genesis, privValidator *types.PrivValidator, validators []types.GenesisValidator) error {
+func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genesis, privValidator *types.PrivValidator, validators []types.GenesisValidator, genesisParams *ChildChainGenesisParams) error {
 	genFile := config.GetString("genesis_file")
 	if _, err := os.Stat(genFile); os.IsNotExist(err) {
 
@@ -237,9 +236,12 @@ func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genes
 			rewardScheme = types.RewardSchemeDoc{
 				TotalReward:        big.NewInt(0),
 				RewardFirstYear:    big.NewInt(0),
-				EpochNumberPerYear: 12,
+				EpochNumberPerYear: defaultEpochNumberPerYear,
 				TotalYear:          0,
 			}
+			if genesisParams != nil && genesisParams.EpochNumberPerYear > 0 {
+				rewardScheme.EpochNumberPerYear = genesisParams.EpochNumberPerYear
+			}
 		}
 
 		var rewardPerBlock *big.Int
@@ -249,6 +251,11 @@ func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genes
 			rewardPerBlock = big.NewInt(0)
 		}
 
+		endBlock := defaultChildChainEpochLength
+		if genesisParams != nil && genesisParams.EpochLength > 0 {
+			endBlock = genesisParams.EpochLength
+		}
+
 		genDoc := types.GenesisDoc{
 			ChainID:      chainId,
 			Consensus:    types.CONSENSUS_POS,
@@ -258,11 +265,15 @@ func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genes
 				Number:         0,
 				RewardPerBlock: rewardPerBlock,
 				StartBlock:     0,
-				EndBlock:       657000,
+				EndBlock:       endBlock,
 				Status:         0,
 			},
 		}
 
+		if genesisParams != nil {
+			genDoc.ValidatorSetRules = genesisParams.ValidatorSetRules
+		}
+
 		if privValidator != nil {
 			coinbase, amount, checkErr := checkAccount(*coreGenesis)
 			if checkErr != nil {
@@ -339,7 +350,7 @@ func checkAccount(coreGenesis core.Genesis) (common.Address, *big.Int, error) {
 	return act, amount, nil
 }
 
-func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Config, validators []types.GenesisValidator) error {
+func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Config, validators []types.GenesisValidator, genesisParams *ChildChainGenesisParams) error {
 
 	var coreGenesis = core.Genesis{
 		Config:     params.NewChildChainConfig(childChainID),
@@ -347,12 +358,22 @@ func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Confi
 		Timestamp:  0x0,
 		ParentHash: common.Hash{},
 		ExtraData:  []byte("0x0"),
-		GasLimit:   0x8000000,
+		GasLimit:   defaultChildChainGasLimit,
 		Difficulty: new(big.Int).SetUint64(0x400),
 		Mixhash:    common.Hash{},
 		Coinbase:   common.Address{},
 		Alloc:      core.GenesisAlloc{},
 	}
+	if genesisParams.GasLimit > 0 {
+		coreGenesis.GasLimit = genesisParams.GasLimit
+	}
+	if genesisParams.RewardPerBlock != nil {
+		coreGenesis.RewardPerBlock = (*big.Int)(genesisParams.RewardPerBlock)
+	}
+	if genesisParams.RewardSchedule != nil {
+		coreGenesis.RewardSchedule = genesisParams.RewardSchedule
+	}
+
 	for _, validator := range validators {
 		coreGenesis.Alloc[validator.EthAccount] = core.GenesisAccount{
 			Balance: big.NewInt(0),
@@ -360,11 +381,8 @@ func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Confi
 		}
 	}
 
-	// Add Child Chain Default Token
-	coreGenesis.Alloc[abi.ChildChainTokenIncentiveAddr] = core.GenesisAccount{
-		Balance: new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e+18)),
-		Amount:  common.Big0,
-	}
+	// Add Pre-allocated Accounts and Child Chain Default Token
+	genesisParams.applyAlloc(coreGenesis.Alloc, new(big.Int).Mul(big.NewInt(100000), big.NewInt(1e+18)))
 
 	contents, err := json.Marshal(coreGenesis)
 	if err != nil {
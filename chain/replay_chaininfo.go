@@ -0,0 +1,210 @@
+package chain
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/cmd/geth"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	tdmTypes "github.com/ethereum/go-ethereum/consensus/tendermint/types"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	dbm "github.com/tendermint/go-db"
+	"gopkg.in/urfave/cli.v1"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+const replayChainInfoDBName = "chaininfo_replay"
+
+// replayStat counts the replayed blocks and ops of one chain
+type replayStat struct {
+	blocks  uint64 // blocks having the ops recorded
+	applied int
+	failed  int // ops failed originally, not replayed
+	skipped int // ops not decoded or not touching the chain info
+	errors  int // ops failed in the replay
+}
+
+// ReplayChainInfoCmd rebuilds the chain info db from the pending ops recorded in the chain history of the main chain
+// and the child chains in the data dir. The node must be stopped, the rebuilt db is written next to the chain info db
+// for comparing or replacing it. It refuses to replay a chain having blocks imported before the ops were recorded.
+func ReplayChainInfoCmd(ctx *cli.Context) error {
+
+	datadir := utils.MakeDataDir(ctx)
+	if _, err := os.Stat(filepath.Join(datadir, replayChainInfoDBName+".db")); err == nil {
+		return fmt.Errorf("%s.db exists in %s, remove it before replaying", replayChainInfoDBName, datadir)
+	}
+
+	mainChainId := MainChain
+	if ctx.GlobalBool(utils.TestnetFlag.Name) {
+		mainChainId = TestnetChain
+	}
+
+	if _, err := os.Stat(chainDataPath(datadir, mainChainId)); err != nil {
+		return fmt.Errorf("chain data of %s not found: %v", mainChainId, err)
+	}
+
+	db := dbm.NewDB(replayChainInfoDBName, dbm.LevelDBBackendStr, datadir)
+	defer db.Close()
+	cch := &CrossChainHelper{chainInfoDB: db, mainChainId: mainChainId}
+
+	// The child chains are launched by the main chain, replay it first
+	stat, err := replayChain(datadir, mainChainId, cch)
+	if err != nil {
+		return err
+	}
+	printReplayStat(mainChainId, stat)
+
+	for _, chainId := range core.GetChildChainIds(db) {
+		if _, err := os.Stat(chainDataPath(datadir, chainId)); err != nil {
+			fmt.Printf("%s: chain data not found, skipped\n", chainId)
+			continue
+		}
+		stat, err := replayChain(datadir, chainId, cch)
+		if err != nil {
+			return err
+		}
+		printReplayStat(chainId, stat)
+	}
+
+	fmt.Printf("chain info rebuilt in %s\n", filepath.Join(datadir, replayChainInfoDBName+".db"))
+	return nil
+}
+
+func chainDataPath(datadir, chainId string) string {
+	return filepath.Join(datadir, chainId, gethmain.ClientIdentifier, "chaindata")
+}
+
+// replayChain replays the recorded ops of the canonical blocks of the chain
+func replayChain(datadir, chainId string, cch *CrossChainHelper) (*replayStat, error) {
+
+	chainDb, err := rawdb.NewLevelDBDatabase(chainDataPath(datadir, chainId), 0, 0, "eth/db/chaindata/")
+	if err != nil {
+		return nil, fmt.Errorf("could not open the chain data of %s: %v", chainId, err)
+	}
+	defer chainDb.Close()
+
+	config := rawdb.ReadChainConfig(chainDb, rawdb.ReadCanonicalHash(chainDb, 0))
+	if config == nil {
+		return nil, fmt.Errorf("chain config of %s not found", chainId)
+	}
+
+	stat := &replayStat{}
+	head := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb))
+	if head == nil || *head == 0 {
+		return stat, nil
+	}
+	// The blocks without records have no op touching the chain info, unless they are below the tail
+	if tail := rawdb.ReadPendingOpsTail(chainDb); tail == nil || *tail > 1 {
+		return nil, fmt.Errorf("pending ops of %s not recorded from the first block, resync the chain to replay it", chainId)
+	}
+	for number := uint64(1); number <= *head; number++ {
+		records := readCanonicalPendingOps(chainDb, number)
+		if len(records) == 0 {
+			continue
+		}
+		stat.blocks++
+		for _, record := range records {
+			if record.Error != "" {
+				stat.failed++
+				continue
+			}
+			op, err := record.PendingOp()
+			if err != nil {
+				log.Warnf("Replay chain info - %s block %v: %v", chainId, number, err)
+				stat.skipped++
+				continue
+			}
+			replayed, err := replayOp(op, number, config, cch)
+			if err != nil {
+				log.Errorf("Replay chain info - %s block %v, %v failed: %v", chainId, number, op, err)
+				stat.errors++
+			} else if replayed {
+				stat.applied++
+			} else {
+				stat.skipped++
+			}
+		}
+	}
+	return stat, nil
+}
+
+func readCanonicalPendingOps(db ethdb.Reader, number uint64) []*types.PendingOpRecord {
+	hash := rawdb.ReadCanonicalHash(db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return rawdb.ReadPendingOps(db, hash, number)
+}
+
+// replayOp applies the op touching the chain info db like core.ApplyOp, it returns false for the other ops
+func replayOp(op types.PendingOp, number uint64, config *params.ChainConfig, cch *CrossChainHelper) (bool, error) {
+	switch op := op.(type) {
+	case *types.CreateChildChainOp:
+		return true, cch.CreateChildChain(op.From, op.ChainId, op.MinValidators, op.MinDepositAmount, op.StartBlock, op.EndBlock, op.GenesisParams)
+	case *types.JoinChildChainOp:
+		return true, cch.JoinChildChain(op.From, op.PubKey, op.ChainId, op.DepositAmount, op.TxHash)
+	case *types.LaunchChildChainsOp:
+		if op.NewPendingIdx != nil || len(op.DeleteChildChainIds) > 0 {
+			cch.ProcessPostPendingData(op.NewPendingIdx, op.DeleteChildChainIds)
+		}
+		// The chain manager formalizes the launched child chain when it starts, the epoch is saved later
+		// by the first SaveDataToMainChain of the child chain
+		for _, chainId := range op.ChildChainIds {
+			if cci := core.GetPendingChildChainData(cch.chainInfoDB, chainId); cci != nil {
+				core.DeletePendingChildChainData(cch.chainInfoDB, chainId)
+				core.SaveChainInfo(cch.chainInfoDB, &core.ChainInfo{CoreChainInfo: *cci})
+			}
+		}
+		return true, nil
+	case *types.CloseChildChainOp:
+		return true, cch.CloseChildChain(op.From, op.ChainId, new(big.Int).SetUint64(number), config.Tendermint.CloseGracePeriod())
+	case *types.RetireChildChainsOp:
+		cch.ProcessPostRetireData(op.ChildChainIds)
+		return true, nil
+	case *types.SaveDataToMainChainOp:
+		// SaveChildChainProofDataToMainChain waits for the child chain to be launched, which never happens in the replay
+		chainId, err := proofDataChainId(op.Data)
+		if err != nil {
+			return true, err
+		}
+		if core.GetChainInfo(cch.chainInfoDB, chainId) == nil {
+			return true, fmt.Errorf("child chain %s not launched", chainId)
+		}
+		return true, cch.SaveChildChainProofDataToMainChain(op.Data)
+	case *types.ChainBalanceStatOp:
+		return true, core.ApplyChainBalanceStatOp(cch.chainInfoDB, op)
+	case *types.FundRewardPoolOp:
+		return true, core.AddRewardPoolFunding(cch.chainInfoDB, op.ChainId, core.RewardPoolFunding{
+			From:        op.From,
+			Amount:      op.Amount,
+			TxHash:      op.TxHash,
+			BlockNumber: number,
+		})
+	default:
+		return false, nil
+	}
+}
+
+func proofDataChainId(bs []byte) (string, error) {
+	var proofData types.ChildChainProofData
+	if err := rlp.DecodeBytes(bs, &proofData); err != nil {
+		return "", err
+	}
+	tdmExtra, err := tdmTypes.ExtractTendermintExtra(proofData.Header)
+	if err != nil {
+		return "", err
+	}
+	return tdmExtra.ChainID, nil
+}
+
+func printReplayStat(chainId string, stat *replayStat) {
+	fmt.Printf("%s: %v blocks with ops, %v ops replayed, %v failed originally, %v skipped, %v failed in the replay\n",
+		chainId, stat.blocks, stat.applied, stat.failed, stat.skipped, stat.errors)
+}
@@ -0,0 +1,250 @@
+package chain
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"math/big"
+	"sync"
+	"time"
+)
+
+var (
+	solvencyAuditTimer     = metrics.NewRegisteredTimer("chain/solvency/audit", nil)
+	solvencyViolationGauge = metrics.NewRegisteredGauge("chain/solvency/violations", nil)
+)
+
+// solvencyAuditor keeps the balance statistics recomputed from the chain history,
+// so that the following audits only need to scan the new blocks
+type solvencyAuditor struct {
+	mtx   sync.Mutex
+	scans map[string]*chainScan // by the id of the scanned chain
+}
+
+// chainScan is the balance statistics of the blocks [1, height] of a chain, it is not changed once cached
+type chainScan struct {
+	height uint64
+	stats  map[string]*core.ChainBalanceStat
+}
+
+// scan bring the statistics of the chain up to the height. The blocks are scanned without holding the lock,
+// the result replaces the cached one unless a concurrent audit has scanned further.
+func (a *solvencyAuditor) scan(chainId string, bc *core.BlockChain, height uint64, depositOf func(txHash common.Hash) *big.Int) (*chainScan, error) {
+	a.mtx.Lock()
+	cached, ok := a.scans[chainId]
+	a.mtx.Unlock()
+	if !ok {
+		cached = &chainScan{stats: make(map[string]*core.ChainBalanceStat)}
+	}
+	if height <= cached.height {
+		return cached, nil
+	}
+
+	scanned := make(map[string]*core.ChainBalanceStat)
+	if err := core.ScanChainBalanceStat(bc, cached.height+1, height, scanned, depositOf); err != nil {
+		return nil, err
+	}
+	result := &chainScan{height: height, stats: make(map[string]*core.ChainBalanceStat)}
+	mergeChainBalanceStats(result.stats, cached.stats)
+	mergeChainBalanceStats(result.stats, scanned)
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if a.scans == nil {
+		a.scans = make(map[string]*chainScan)
+	}
+	if current, ok := a.scans[chainId]; !ok || current.height < result.height {
+		a.scans[chainId] = result
+	}
+	return result, nil
+}
+
+// AuditSolvency recompute the balance in & out statistics of the child chain (all child chains if chainId is empty)
+// from the chain history, then compare them with the stored statistics and check the invariants
+func (cch *CrossChainHelper) AuditSolvency(chainId string) ([]*core.SolvencyReport, error) {
+	start := time.Now()
+	defer solvencyAuditTimer.UpdateSince(start)
+
+	var chainIds []string
+	if chainId == "" {
+		chainIds = core.GetChildChainIds(cch.chainInfoDB)
+	} else {
+		if core.GetChainInfo(cch.chainInfoDB, chainId) == nil {
+			return nil, fmt.Errorf("child chain %s not exist", chainId)
+		}
+		chainIds = []string{chainId}
+	}
+
+	// Main Chain Side
+	mainChain := MustGetEthereumFromNode(chainMgr.mainChain.EthNode).BlockChain()
+	mainScan, err := cch.solvency.scan(cch.mainChainId, mainChain, mainChain.CurrentBlock().NumberU64(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("scan main chain failed: %v", err)
+	}
+
+	mainState, err := mainChain.State()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*core.SolvencyReport, 0, len(chainIds))
+	ownerBacking := make(map[common.Address]*big.Int)
+	ownerEscrow := make(map[common.Address]*big.Int)
+	for _, id := range chainIds {
+		ci := core.GetChainInfo(cch.chainInfoDB, id)
+		if ci == nil {
+			continue
+		}
+
+		report := &core.SolvencyReport{
+			ChainId:           id,
+			Owner:             ci.Owner,
+			MainChainHeight:   mainScan.height,
+			Stored:            ci.BalanceStat(),
+			Computed:          core.NewChainBalanceStat(),
+			OwnerChainBalance: mainState.GetChainBalance(ci.Owner),
+			RewardPoolEscrow:  mainState.GetChainBalance(core.RewardPoolAddress(id)),
+			StatTracked:       ci.BalanceStatTracked,
+		}
+		if stat, ok := mainScan.stats[id]; ok {
+			report.Computed.DepositInMainChain.Set(stat.DepositInMainChain)
+			report.Computed.WithdrawFromMainChain.Set(stat.WithdrawFromMainChain)
+			report.Computed.FundRewardPool.Set(stat.FundRewardPool)
+		}
+
+		// Child Chain Side, only available when the child chain is running in this node
+		childHeight, childStat, err := cch.scanChildChain(id)
+		if err != nil {
+			log.Warnf("Solvency audit: scan child chain %s failed: %v", id, err)
+		} else if childStat != nil {
+			report.ChildChainHeight = childHeight
+			report.Computed.DepositInChildChain.Set(childStat.DepositInChildChain)
+			report.Computed.WithdrawFromChildChain.Set(childStat.WithdrawFromChildChain)
+		}
+		childAudited := report.ChildChainHeight > 0
+
+		// The stored statistics of the chain created before they were introduced miss the early history
+		if report.StatTracked {
+			report.Violations = append(report.Violations, report.Stored.Compare(report.Computed, childAudited)...)
+		}
+		report.Violations = append(report.Violations, report.Computed.CheckInvariants(childAudited)...)
+
+		// The pending withdrawal must be payable by the chain balance and the reward pool escrow
+		if childAudited {
+			pending := new(big.Int).Sub(report.Computed.WithdrawFromChildChain, report.Computed.WithdrawFromMainChain)
+			if escrow := new(big.Int).Add(report.OwnerChainBalance, report.RewardPoolEscrow); pending.Cmp(escrow) > 0 {
+				report.Violations = append(report.Violations, fmt.Sprintf("pending withdrawal (%v) exceeds the chain balance of the owner and the reward pool escrow (%v)",
+					pending, escrow))
+			}
+		}
+
+		// Net deposit of all the chains from the same owner are backed by the owner's chain balance and the reward pool escrows
+		backing, ok := ownerBacking[ci.Owner]
+		if !ok {
+			backing = new(big.Int)
+			ownerBacking[ci.Owner] = backing
+			ownerEscrow[ci.Owner] = new(big.Int).Set(report.OwnerChainBalance)
+		}
+		ownerEscrow[ci.Owner].Add(ownerEscrow[ci.Owner], report.RewardPoolEscrow)
+		backing.Add(backing, report.Computed.DepositInMainChain)
+		backing.Add(backing, report.Computed.FundRewardPool)
+		backing.Sub(backing, report.Computed.WithdrawFromMainChain)
+
+		reports = append(reports, report)
+	}
+
+	// Only check the backing when all the chains of the owner are audited
+	if chainId == "" {
+		for _, report := range reports {
+			if backing, escrow := ownerBacking[report.Owner], ownerEscrow[report.Owner]; backing.Cmp(escrow) > 0 {
+				report.Violations = append(report.Violations, fmt.Sprintf("net deposit of the owner's chains (%v) exceeds the chain balance of the owner and the reward pool escrows (%v)",
+					backing, escrow))
+			}
+		}
+	}
+
+	return reports, nil
+}
+
+// scanChildChain scan the new blocks of the child chain, return nil statistics if the child chain is not running in this node
+func (cch *CrossChainHelper) scanChildChain(chainId string) (uint64, *core.ChainBalanceStat, error) {
+
+	chainMgr.createChildChainLock.Lock()
+	chain, ok := chainMgr.childChains[chainId]
+	chainMgr.createChildChainLock.Unlock()
+	if !ok {
+		return 0, nil, nil
+	}
+
+	ethereum, err := getEthereumFromNode(chain.EthNode)
+	if err != nil {
+		return 0, nil, nil
+	}
+	childChain := ethereum.BlockChain()
+
+	depositOf := func(txHash common.Hash) *big.Int {
+		if tx := cch.GetTxFromMainChain(txHash); tx != nil {
+			return tx.Value()
+		}
+		return nil
+	}
+	childScan, err := cch.solvency.scan(chainId, childChain, childChain.CurrentBlock().NumberU64(), depositOf)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Only the txs of this child chain are counted
+	stat, ok := childScan.stats[chainId]
+	if !ok {
+		stat = core.NewChainBalanceStat()
+	}
+	return childScan.height, stat, nil
+}
+
+func mergeChainBalanceStats(dst, src map[string]*core.ChainBalanceStat) {
+	for id, stat := range src {
+		if _, ok := dst[id]; !ok {
+			dst[id] = core.NewChainBalanceStat()
+		}
+		dst[id].Merge(stat)
+	}
+}
+
+// StartSolvencyAudit run the solvency audit of all the child chains periodically,
+// the violations are reported by the logs and the metrics
+func (cm *ChainManager) StartSolvencyAudit(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reports, err := cm.cch.AuditSolvency("")
+				if err != nil {
+					log.Errorf("Solvency audit failed: %v", err)
+					continue
+				}
+
+				total := 0
+				for _, report := range reports {
+					for _, violation := range report.Violations {
+						log.Errorf("Solvency audit: chain %s, %s", report.ChainId, violation)
+					}
+					metrics.GetOrRegisterGauge("chain/solvency/violations/"+report.ChainId, nil).Update(int64(len(report.Violations)))
+					total += len(report.Violations)
+				}
+				solvencyViolationGauge.Update(int64(total))
+				log.Infof("Solvency audit done, %v child chains audited, %v violations found", len(reports), total)
+			case <-cm.stop:
+				return
+			}
+		}
+	}()
+}
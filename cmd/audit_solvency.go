@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+	"net"
+	"strconv"
+)
+
+// AuditSolvencyCmd run the solvency audit of the child chain (all child chains if chainId is omitted) through the RPC of the running node,
+// it fails if any violation is found
+func AuditSolvencyCmd(ctx *cli.Context) error {
+
+	chainId := ctx.Args().First()
+
+	mainChainId := params.MainnetChainConfig.PChainId
+	if ctx.GlobalBool(utils.TestnetFlag.Name) {
+		mainChainId = params.TestnetChainConfig.PChainId
+	}
+
+	host := "127.0.0.1"
+	port := ctx.GlobalInt(utils.RPCPortFlag.Name)
+	url := "http://" + net.JoinHostPort(host, strconv.Itoa(port)) + "/" + mainChainId
+
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return fmt.Errorf("can't connect to %s, err: %v", url, err)
+	}
+	defer client.Close()
+
+	var reports []struct {
+		ChainID    string   `json:"chain_id"`
+		Violations []string `json:"violations"`
+	}
+	var raw json.RawMessage
+	if err := client.CallContext(context.Background(), &raw, "chain_auditSolvency", chainId); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, &reports); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	violations := 0
+	for _, report := range reports {
+		violations += len(report.Violations)
+	}
+	if violations > 0 {
+		return fmt.Errorf("%v violations found in %v child chains", violations, len(reports))
+	}
+	return nil
+}
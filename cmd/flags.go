@@ -23,6 +23,12 @@ var (
 		Usage: "Specify one or more child chain should be start. Ex: child-1,child-2",
 	}
 
+	// Solvency Audit Flag
+	SolvencyAuditIntervalFlag = cli.DurationFlag{
+		Name:  "solvencyAuditInterval",
+		Usage: "Interval of the cross chain solvency audit of the child chains, disabled by default",
+	}
+
 	// ----------------------------
 	// Tendermint Flags
 
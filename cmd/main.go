@@ -50,6 +50,20 @@ func main() {
 			Description: "Initialize the files",
 		},
 
+		{
+			Action:      AuditSolvencyCmd,
+			Name:        "audit_solvency",
+			Usage:       "audit_solvency [chainId]", //audit all child chains if chainId is omitted
+			Description: "Audit the cross chain solvency of the child chains through the RPC of the running node",
+		},
+
+		{
+			Action:      chain.ReplayChainInfoCmd,
+			Name:        "replay_chaininfo",
+			Usage:       "replay_chaininfo",
+			Description: "Rebuild the chain info db from the pending ops recorded in the chain history, the node must be stopped",
+		},
+
 		{
 			Action:      GenerateNodeInfoCmd,
 			Name:        "gen_node_info",
@@ -75,6 +89,7 @@ func main() {
 
 		//walletCommand,
 		accountCommand,
+		txCommand,
 	}
 	cliApp.HideVersion = true // we have a command to print the version
 
@@ -158,6 +173,7 @@ func newCliApp(version, usage string) *cli.App {
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolFunctionSlotsFlag,
 		//utils.FastSyncFlag,
 		//utils.LightModeFlag,
 		utils.SyncModeFlag,
@@ -192,6 +208,7 @@ func newCliApp(version, usage string) *cli.App {
 		utils.NetworkIdFlag,
 		utils.PruneFlag,
 		//utils.PruneBlockFlag,
+		utils.RewardHistoryFlag,
 
 		utils.EthStatsURLFlag,
 		utils.MetricsEnabledFlag,
@@ -229,6 +246,7 @@ func newCliApp(version, usage string) *cli.App {
 
 		LogDirFlag,
 		ChildChainFlag,
+		SolvencyAuditIntervalFlag,
 
 		/*
 			//Tendermint flags
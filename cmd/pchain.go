@@ -74,6 +74,8 @@ func pchainCmd(ctx *cli.Context) error {
 
 	chainMgr.StartInspectEvent()
 
+	chainMgr.StartSolvencyAudit(ctx.GlobalDuration(SolvencyAuditIntervalFlag.Name))
+
 	go func() {
 		sigc := make(chan os.Signal, 1)
 		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
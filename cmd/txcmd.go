@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/pchainclient"
+	"github.com/ethereum/go-ethereum/rlp"
+	pabi "github.com/pchain/abi"
+	"gopkg.in/urfave/cli.v1"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+var (
+	txChainIdFlag = cli.StringFlag{
+		Name:  "chainId",
+		Usage: "PChain chain id the transaction is sent to, the main chain (or testnet) if omitted",
+	}
+	txNonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the transaction, fetched from the node by --from if omitted",
+	}
+	txFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Sender address, used to fetch the nonce and the gas price from the node when they are omitted",
+	}
+	txGasPriceFlag = cli.StringFlag{
+		Name:  "gasPrice",
+		Usage: "Gas price in wei, suggested by the node if omitted",
+	}
+	txGasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "Gas limit, the required gas of the function if omitted",
+	}
+	txValueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Value in wei sent with the transaction (delegation amount, deposit, ...), the creation value for CreateChildChain if omitted",
+	}
+	txOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "File the transaction is written to, stdout if omitted",
+	}
+	txKeyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Keystore file of the sender",
+	}
+	txRPCURLFlag = cli.StringFlag{
+		Name:  "rpcurl",
+		Usage: "RPC endpoint of the node, the local node of the chain (by --rpcport) if omitted",
+	}
+
+	txCommand = cli.Command{
+		Name:     "tx",
+		Usage:    "Build, sign offline and broadcast the PChain special transactions",
+		Category: "TRANSACTION COMMANDS",
+		Description: `
+The special transactions (delegation, candidate, vote, cross chain ...) can be signed
+on an offline machine holding the keystore file, instead of unlocking the account in the node.
+
+    pchain tx build --nonce 5 --gasPrice 1000000000 --value 1000000000000000000000 --out tx.json Delegate 0x<candidate>
+    pchain tx sign --keyfile UTC--... --out signed.json tx.json
+    pchain tx broadcast signed.json
+
+The amounts are in wei, the bytes and the hashes are in hex.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "build",
+				Usage:     "Build the unsigned transaction of the chain function",
+				Action:    utils.MigrateFlags(txBuild),
+				ArgsUsage: "<function> [args...]",
+				Flags: []cli.Flag{
+					txChainIdFlag,
+					txNonceFlag,
+					txFromFlag,
+					txGasPriceFlag,
+					txGasFlag,
+					txValueFlag,
+					txOutFlag,
+					txRPCURLFlag,
+				},
+				Description: `
+    pchain tx build [options] <function> [args...]
+
+Packs the function of the PChain chain ABI with the args in the ABI order and builds the
+transaction to the chain contract address. Without --nonce or --gasPrice the node is asked
+for them with --from, otherwise no connection is made.`,
+			},
+			{
+				Name:      "sign",
+				Usage:     "Sign the transaction offline with the keystore file",
+				Action:    utils.MigrateFlags(txSign),
+				ArgsUsage: "<txFile>",
+				Flags: []cli.Flag{
+					txKeyFileFlag,
+					txOutFlag,
+					utils.PasswordFileFlag,
+				},
+				Description: `
+    pchain tx sign --keyfile <keyfile> [options] <txFile>
+
+Signs the transaction built by 'pchain tx build' with the EIP155 signer of its chain,
+you are prompted for the passphrase of the keystore file unless --password is given.`,
+			},
+			{
+				Name:      "broadcast",
+				Usage:     "Broadcast the signed transaction through the node",
+				Action:    utils.MigrateFlags(txBroadcast),
+				ArgsUsage: "<txFile>",
+				Flags: []cli.Flag{
+					txRPCURLFlag,
+				},
+				Description: `
+    pchain tx broadcast [options] <txFile>
+
+Sends the transaction signed by 'pchain tx sign' with eth_sendRawTransaction.`,
+			},
+		},
+	}
+)
+
+// offlineTx is the transaction file passed between the build, sign and broadcast commands
+type offlineTx struct {
+	ChainId  string          `json:"chainId"`
+	Function string          `json:"function"`
+	From     *common.Address `json:"from,omitempty"`
+	Hash     *common.Hash    `json:"hash,omitempty"`
+	Tx       hexutil.Bytes   `json:"tx"`
+}
+
+func txBuild(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return errors.New("function is required")
+	}
+	function := ctx.Args().First()
+	method, ok := pabi.ChainABI.Methods[function]
+	if !ok {
+		return fmt.Errorf("unknown chain function %q", function)
+	}
+	args, err := parseChainFunctionArgs(method, ctx.Args().Tail())
+	if err != nil {
+		return err
+	}
+
+	chainId := txChainId(ctx)
+	value, err := parseBigFlag(ctx, txValueFlag.Name)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		value = pchainclient.DefaultValue(function)
+	}
+	gasPrice, err := parseBigFlag(ctx, txGasPriceFlag.Name)
+	if err != nil {
+		return err
+	}
+
+	builder := pchainclient.NewTxBuilder(chainId, ctx.Uint64(txNonceFlag.Name), gasPrice)
+	builder.Gas = ctx.Uint64(txGasFlag.Name)
+	if !ctx.IsSet(txNonceFlag.Name) || gasPrice == nil {
+		if !common.IsHexAddress(ctx.String(txFromFlag.Name)) {
+			return errors.New("--from is required to fetch the nonce or the gas price from the node")
+		}
+		client, err := pchainclient.Dial(txRPCURL(ctx, chainId))
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		remote, err := client.NewTxBuilder(context.Background(), chainId, common.HexToAddress(ctx.String(txFromFlag.Name)))
+		if err != nil {
+			return err
+		}
+		if !ctx.IsSet(txNonceFlag.Name) {
+			builder.Nonce = remote.Nonce
+		}
+		if gasPrice == nil {
+			builder.GasPrice = remote.GasPrice
+		}
+	}
+
+	tx, err := builder.Build(function, value, args...)
+	if err != nil {
+		return err
+	}
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	return writeOfflineTx(ctx, &offlineTx{ChainId: chainId, Function: function, Tx: data})
+}
+
+func txSign(ctx *cli.Context) error {
+	otx, tx, err := readOfflineTx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printOfflineTx(otx, tx); err != nil {
+		return err
+	}
+
+	keyFile := ctx.String(txKeyFileFlag.Name)
+	if keyFile == "" {
+		return errors.New("--keyfile is required")
+	}
+	keyJSON, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	passphrase := getPassPhrase("", false, 0, utils.MakePasswordList(ctx))
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return fmt.Errorf("can't decrypt the keystore file: %v", err)
+	}
+
+	signed, err := types.SignTx(tx, pchainclient.NewSigner(otx.ChainId), key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return err
+	}
+	hash := signed.Hash()
+	otx.From, otx.Hash, otx.Tx = &key.Address, &hash, data
+	return writeOfflineTx(ctx, otx)
+}
+
+func txBroadcast(ctx *cli.Context) error {
+	otx, tx, err := readOfflineTx(ctx)
+	if err != nil {
+		return err
+	}
+	from, err := types.Sender(pchainclient.NewSigner(otx.ChainId), tx)
+	if err != nil {
+		return fmt.Errorf("transaction not signed for chain %s: %v", otx.ChainId, err)
+	}
+
+	client, err := pchainclient.Dial(txRPCURL(ctx, otx.ChainId))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		return err
+	}
+	fmt.Printf("%s from %x sent, tx hash %x\n", otx.Function, from, tx.Hash())
+	return nil
+}
+
+// txChainId returns the chain id by the flag, the main chain (or testnet) by default
+func txChainId(ctx *cli.Context) string {
+	if chainId := ctx.String(txChainIdFlag.Name); chainId != "" {
+		return chainId
+	}
+	if ctx.GlobalBool(utils.TestnetFlag.Name) {
+		return params.TestnetChainConfig.PChainId
+	}
+	return params.MainnetChainConfig.PChainId
+}
+
+// txRPCURL returns the rpc url by the flag, the chain's endpoint of the local node by default
+func txRPCURL(ctx *cli.Context, chainId string) string {
+	if url := ctx.String(txRPCURLFlag.Name); url != "" {
+		return url
+	}
+	port := ctx.GlobalInt(utils.RPCPortFlag.Name)
+	return "http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) + "/" + chainId
+}
+
+func parseBigFlag(ctx *cli.Context, name string) (*big.Int, error) {
+	s := ctx.String(name)
+	if s == "" {
+		return nil, nil
+	}
+	v, ok := math.ParseBig256(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid --%s %q", name, s)
+	}
+	return v, nil
+}
+
+// parseChainFunctionArgs converts the command line args to the types of the method inputs
+func parseChainFunctionArgs(method abi.Method, args []string) ([]interface{}, error) {
+	if len(args) != len(method.Inputs) {
+		var names []string
+		for _, input := range method.Inputs {
+			names = append(names, input.Name+" "+input.Type.String())
+		}
+		return nil, fmt.Errorf("%s requires %d args %v, got %d", method.Name, len(method.Inputs), names, len(args))
+	}
+
+	values := make([]interface{}, 0, len(args))
+	for i, input := range method.Inputs {
+		s := args[i]
+		var v interface{}
+		var err error
+		switch input.Type.T {
+		case abi.AddressTy:
+			if !common.IsHexAddress(s) {
+				err = errors.New("invalid address")
+			}
+			v = common.HexToAddress(s)
+		case abi.BoolTy:
+			v, err = strconv.ParseBool(s)
+		case abi.StringTy:
+			v = s
+		case abi.BytesTy:
+			v, err = hexutil.Decode(s)
+		case abi.FixedBytesTy:
+			var b []byte
+			if b, err = hexutil.Decode(s); err == nil && len(b) != input.Type.Size {
+				err = fmt.Errorf("%d bytes required", input.Type.Size)
+			}
+			// bytesN is packed from [N]byte
+			array := reflect.New(input.Type.Type).Elem()
+			reflect.Copy(array, reflect.ValueOf(b))
+			v = array.Interface()
+		case abi.UintTy:
+			if input.Type.Size > 64 {
+				var ok bool
+				if v, ok = math.ParseBig256(s); !ok {
+					err = errors.New("invalid number")
+				}
+				break
+			}
+			var n uint64
+			n, err = strconv.ParseUint(s, 0, input.Type.Size)
+			switch input.Type.Size {
+			case 8:
+				v = uint8(n)
+			case 16:
+				v = uint16(n)
+			case 32:
+				v = uint32(n)
+			default:
+				v = n
+			}
+		default:
+			err = errors.New("unsupported type")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("arg %s (%s) %q: %v", input.Name, input.Type.String(), s, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func readOfflineTx(ctx *cli.Context) (*offlineTx, *types.Transaction, error) {
+	if ctx.NArg() != 1 {
+		return nil, nil, errors.New("transaction file is required")
+	}
+	data, err := ioutil.ReadFile(ctx.Args().First())
+	if err != nil {
+		return nil, nil, err
+	}
+	otx := new(offlineTx)
+	if err := json.Unmarshal(data, otx); err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction file: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(otx.Tx, tx); err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction: %v", err)
+	}
+	return otx, tx, nil
+}
+
+func writeOfflineTx(ctx *cli.Context, otx *offlineTx) error {
+	out, err := json.MarshalIndent(otx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if file := ctx.String(txOutFlag.Name); file != "" {
+		return ioutil.WriteFile(file, append(out, '\n'), 0600)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// printOfflineTx prints the decoded transaction to stderr for the review before signing
+func printOfflineTx(otx *offlineTx, tx *types.Transaction) error {
+	if tx.To() == nil || *tx.To() != pabi.ChainContractMagicAddr || len(tx.Data()) < 4 {
+		return errors.New("not a chain function transaction")
+	}
+	method, err := pabi.ChainABI.MethodById(tx.Data()[:4])
+	if err != nil {
+		return err
+	}
+	args, err := method.Inputs.UnpackValues(tx.Data()[4:])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Chain:     %s\n", otx.ChainId)
+	fmt.Fprintf(os.Stderr, "Function:  %s\n", method.Name)
+	for i, input := range method.Inputs {
+		arg := args[i]
+		switch v := arg.(type) {
+		case []byte:
+			arg = hexutil.Bytes(v)
+		case common.Address:
+			arg = v.Hex()
+		case [32]byte:
+			arg = common.Hash(v).Hex()
+		}
+		fmt.Fprintf(os.Stderr, "  %-16s %v\n", input.Name, arg)
+	}
+	fmt.Fprintf(os.Stderr, "Nonce:     %d\n", tx.Nonce())
+	fmt.Fprintf(os.Stderr, "Value:     %v\n", tx.Value())
+	fmt.Fprintf(os.Stderr, "Gas:       %d\n", tx.Gas())
+	fmt.Fprintf(os.Stderr, "Gas Price: %v\n", tx.GasPrice())
+	return nil
+}
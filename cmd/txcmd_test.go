@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testTxABI = `[{"type":"function","name":"Test","constant":false,"inputs":[
+	{"name":"hash","type":"bytes32"},
+	{"name":"short","type":"bytes4"},
+	{"name":"amount","type":"uint256"},
+	{"name":"count","type":"uint16"},
+	{"name":"addr","type":"address"}
+],"outputs":[]}]`
+
+func TestParseChainFunctionArgs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(testTxABI))
+	if err != nil {
+		t.Fatalf("failed to parse the abi: %v", err)
+	}
+	method := parsed.Methods["Test"]
+	hash := common.HexToHash("0x01")
+
+	args, err := parseChainFunctionArgs(method, []string{hash.Hex(), "0x01020304", "1000", "7", "0x000000000000000000000000000000000000000a"})
+	if err != nil {
+		t.Fatalf("failed to parse the args: %v", err)
+	}
+	if _, err := parsed.Pack("Test", args...); err != nil {
+		t.Fatalf("failed to pack the parsed args: %v", err)
+	}
+	if have := args[0].([32]byte); have != hash {
+		t.Errorf("bytes32 mismatch: have %x, want %x", have, hash)
+	}
+	if have := args[1].([4]byte); have != [4]byte{1, 2, 3, 4} {
+		t.Errorf("bytes4 mismatch: have %x", have)
+	}
+	if have := args[3].(uint16); have != 7 {
+		t.Errorf("uint16 mismatch: have %d", have)
+	}
+
+	// The size of bytesN is checked
+	if _, err := parseChainFunctionArgs(method, []string{hash.Hex(), "0x0102", "1000", "7", "0x000000000000000000000000000000000000000a"}); err == nil {
+		t.Errorf("parsed bytes4 from 2 bytes")
+	}
+	if _, err := parseChainFunctionArgs(method, []string{hash.Hex()}); err == nil {
+		t.Errorf("parsed the missing args")
+	}
+}